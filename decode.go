@@ -0,0 +1,185 @@
+package rueidis
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Decode reads a RedisResult into a value of type T, reusing the same field and type
+// conversion rules as RedisMessage.Scan. T may be a primitive (string, the various int
+// and float kinds, bool), a slice, a pointer, time.Time, a type implementing
+// encoding.TextUnmarshaler/json.Unmarshaler, or a struct decoded the same way Scan does.
+func Decode[T any](r RedisResult) (v T, err error) {
+	if err = r.Error(); err != nil {
+		return v, err
+	}
+	if err = scanValue(reflect.ValueOf(&v).Elem(), &r.val); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// AsStrings reads the message as a redis array/set response and returns the string
+// value of each element.
+func (m *RedisMessage) AsStrings() (s []string, err error) {
+	values, err := m.ToArray()
+	if err != nil {
+		return nil, err
+	}
+	s = make([]string, len(values))
+	for i := range values {
+		if s[i], err = values[i].ToString(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// AsStrings delegates to RedisMessage.AsStrings
+func (r RedisResult) AsStrings() ([]string, error) {
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	return r.val.AsStrings()
+}
+
+// AsInt64s reads the message as a redis array/set response and returns the int64 value
+// of each element.
+func (m *RedisMessage) AsInt64s() (s []int64, err error) {
+	values, err := m.ToArray()
+	if err != nil {
+		return nil, err
+	}
+	s = make([]int64, len(values))
+	for i := range values {
+		if s[i], err = scanInt64(&values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// AsInt64s delegates to RedisMessage.AsInt64s
+func (r RedisResult) AsInt64s() ([]int64, error) {
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	return r.val.AsInt64s()
+}
+
+// AsFloat64s reads the message as a redis array/set response and returns the float64
+// value of each element.
+func (m *RedisMessage) AsFloat64s() (s []float64, err error) {
+	values, err := m.ToArray()
+	if err != nil {
+		return nil, err
+	}
+	s = make([]float64, len(values))
+	for i := range values {
+		if s[i], err = scanFloat64(&values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// AsFloat64s delegates to RedisMessage.AsFloat64s
+func (r RedisResult) AsFloat64s() ([]float64, error) {
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	return r.val.AsFloat64s()
+}
+
+// AsStringMap reads the message as a redis RESP3 map response, or a flat array response
+// such as the result of HGETALL, and returns it as a map[string]string.
+func (m *RedisMessage) AsStringMap() (map[string]string, error) {
+	data, err := m.toScanMap()
+	if err != nil {
+		return nil, err
+	}
+	r := make(map[string]string, len(data))
+	for k, v := range data {
+		s, err := v.ToString()
+		if err != nil {
+			return nil, err
+		}
+		r[k] = s
+	}
+	return r, nil
+}
+
+// AsStringMap delegates to RedisMessage.AsStringMap
+func (r RedisResult) AsStringMap() (map[string]string, error) {
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	return r.val.AsStringMap()
+}
+
+// AsStringIntMap reads the message as a redis RESP3 map response, or a flat array
+// response, and returns it as a map[string]int64.
+func (m *RedisMessage) AsStringIntMap() (map[string]int64, error) {
+	data, err := m.toScanMap()
+	if err != nil {
+		return nil, err
+	}
+	r := make(map[string]int64, len(data))
+	for k, v := range data {
+		i, err := scanInt64(&v)
+		if err != nil {
+			return nil, err
+		}
+		r[k] = i
+	}
+	return r, nil
+}
+
+// AsStringIntMap delegates to RedisMessage.AsStringIntMap
+func (r RedisResult) AsStringIntMap() (map[string]int64, error) {
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	return r.val.AsStringIntMap()
+}
+
+// XRangeEntry is a single entry returned by XRANGE/XREVRANGE: a stream entry ID and its
+// field/value pairs.
+type XRangeEntry struct {
+	ID          string
+	FieldValues map[string]string
+}
+
+// AsXRangeEntries parses a XRANGE/XREVRANGE style reply, an array of
+// [id, [field, value, ...]] pairs, into a slice of XRangeEntry.
+func (m *RedisMessage) AsXRangeEntries() ([]XRangeEntry, error) {
+	values, err := m.ToArray()
+	if err != nil {
+		return nil, err
+	}
+	s := make([]XRangeEntry, len(values))
+	for i, entry := range values {
+		pair, err := entry.ToArray()
+		if err != nil {
+			return nil, err
+		}
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("rueidis: XRANGE entry should have 2 elements, got %d", len(pair))
+		}
+		if s[i].ID, err = pair[0].ToString(); err != nil {
+			return nil, err
+		}
+		if s[i].FieldValues, err = pair[1].AsStringMap(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// AsXRangeEntries delegates to RedisMessage.AsXRangeEntries
+func (r RedisResult) AsXRangeEntries() ([]XRangeEntry, error) {
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	return r.val.AsXRangeEntries()
+}
@@ -0,0 +1,99 @@
+package rueidis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// JSONMapKeyError is returned by MarshalJSON when a RESP3 map contains a non-string key,
+// which cannot be represented as a JSON object key.
+type JSONMapKeyError struct {
+	Kind RedisMessageKind
+}
+
+func (e *JSONMapKeyError) Error() string {
+	return fmt.Sprintf("rueidis: redis message type %c as map key cannot be marshalled to JSON", byte(e.Kind))
+}
+
+// MarshalJSON implements json.Marshaler, rendering the whole RESP3 message tree as
+// canonical JSON: strings and verbatim strings become JSON strings, integers/doubles
+// become JSON numbers, big numbers become JSON strings of their decimal digits, booleans become
+// true/false, nil becomes null, arrays/sets/pushes become JSON arrays, and maps become
+// JSON objects. Redis error messages are rendered as {"error":"..."}. If the message
+// carries RESP3 attributes, they are emitted alongside the value under an "_attrs" key.
+func (m *RedisMessage) MarshalJSON() ([]byte, error) {
+	v, err := m.toJSONValue()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// MarshalJSON delegates to RedisMessage.MarshalJSON. The underlying error (ex. network
+// timeout), if any, is returned instead of being rendered as JSON.
+func (r RedisResult) MarshalJSON() ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.val.MarshalJSON()
+}
+
+func (m *RedisMessage) toJSONValue() (v interface{}, err error) {
+	switch m.typ {
+	case '_':
+		v = nil
+	case '-', '!':
+		v = map[string]interface{}{"error": m.string}
+	case '$', '+':
+		v = m.string
+	case '=':
+		s, _, _ := m.ToVerbatimString()
+		v = s
+	case ':':
+		v = m.integer
+	case ',':
+		v, err = m.ToFloat64()
+	case '#':
+		v = m.integer == 1
+	case '(':
+		var bi *big.Int
+		if bi, err = m.ToBigInt(); err == nil {
+			v = bi.String()
+		}
+	case '*', '~', '>':
+		arr := make([]interface{}, len(m.values))
+		for i := range m.values {
+			if arr[i], err = m.values[i].toJSONValue(); err != nil {
+				break
+			}
+		}
+		v = arr
+	case '%':
+		obj := make(map[string]interface{}, len(m.values)/2)
+		for i := 0; i < len(m.values) && err == nil; i += 2 {
+			if m.values[i].typ != '$' && m.values[i].typ != '+' {
+				err = &JSONMapKeyError{Kind: RedisMessageKind(m.values[i].typ)}
+				break
+			}
+			var e interface{}
+			if e, err = m.values[i+1].toJSONValue(); err == nil {
+				obj[m.values[i].string] = e
+			}
+		}
+		v = obj
+	default:
+		err = fmt.Errorf("rueidis: unsupported redis message type %c for JSON marshalling", m.typ)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.attrs != nil {
+		av, aerr := m.attrs.toJSONValue()
+		if aerr != nil {
+			return nil, aerr
+		}
+		return map[string]interface{}{"_value": v, "_attrs": av}, nil
+	}
+	return v, nil
+}
@@ -2,6 +2,7 @@ package rueidis
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 	"unsafe"
@@ -146,6 +147,30 @@ func (r RedisResult) ToMap() (map[string]RedisMessage, error) {
 	return r.val.ToMap()
 }
 
+// ToPush delegates to RedisMessage.ToPush
+func (r RedisResult) ToPush() ([]RedisMessage, error) {
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	return r.val.ToPush()
+}
+
+// ToBigInt delegates to RedisMessage.ToBigInt
+func (r RedisResult) ToBigInt() (*big.Int, error) {
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	return r.val.ToBigInt()
+}
+
+// ToVerbatimString delegates to RedisMessage.ToVerbatimString
+func (r RedisResult) ToVerbatimString() (val string, format string, err error) {
+	if err = r.Error(); err != nil {
+		return "", "", err
+	}
+	return r.val.ToVerbatimString()
+}
+
 // RedisMessage is a redis response message, it may be a nil response
 type RedisMessage struct {
 	string  string
@@ -242,6 +267,63 @@ func (m *RedisMessage) ToMap() (map[string]RedisMessage, error) {
 	panic(fmt.Sprintf("redis message type %c is not a map", m.typ))
 }
 
+// RedisMessageKind identifies the RESP3 type of a RedisMessage.
+type RedisMessageKind byte
+
+// Kind returns the RESP3 type of the message, such as '$', '*', '%', '>', etc.
+func (m *RedisMessage) Kind() RedisMessageKind {
+	return RedisMessageKind(m.typ)
+}
+
+// IsPush check if message is a redis RESP3 push message, ex. client-side-caching invalidation or keyspace notification
+func (m *RedisMessage) IsPush() bool {
+	return m.typ == '>'
+}
+
+// ToPush check if message is a redis RESP3 push message, and return its values
+func (m *RedisMessage) ToPush() ([]RedisMessage, error) {
+	if m.typ == '>' {
+		return m.values, nil
+	}
+	if err := m.Error(); err != nil {
+		return nil, err
+	}
+	panic(fmt.Sprintf("redis message type %c is not a push message", m.typ))
+}
+
+// Attributes returns the RESP3 attributes attached to this message, if any
+func (m *RedisMessage) Attributes() *RedisMessage {
+	return m.attrs
+}
+
+// ToBigInt check if message is a redis RESP3 big number response, and return it
+func (m *RedisMessage) ToBigInt() (val *big.Int, err error) {
+	if m.typ == '(' {
+		if v, ok := new(big.Int).SetString(m.string, 10); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("redis message is not a valid big number: %q", m.string)
+	}
+	if err = m.Error(); err != nil {
+		return nil, err
+	}
+	panic(fmt.Sprintf("redis message type %c is not a big number", m.typ))
+}
+
+// ToVerbatimString check if message is a redis RESP3 verbatim string response, and return its value and format
+func (m *RedisMessage) ToVerbatimString() (val string, format string, err error) {
+	if m.typ == '=' {
+		if len(m.string) >= 4 && m.string[3] == ':' {
+			return m.string[4:], m.string[:3], nil
+		}
+		return m.string, "", nil
+	}
+	if err = m.Error(); err != nil {
+		return "", "", err
+	}
+	panic(fmt.Sprintf("redis message type %c is not a verbatim string", m.typ))
+}
+
 func (m *RedisMessage) approximateSize() (s int) {
 	s += messageStructSize
 	s += len(m.string)
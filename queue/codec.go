@@ -0,0 +1,40 @@
+package queue
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec encodes and decodes queue payloads for Enqueue/Dequeue. Implement this
+// interface to plug in a different wire format, ex. MessagePack.
+type Codec interface {
+	Encode(v interface{}) ([]byte, error)
+	Decode(data []byte, v interface{}) error
+}
+
+// JSONCodec encodes payloads as JSON. It is the default Codec used by New.
+type JSONCodec struct{}
+
+// Encode implements Codec.
+func (JSONCodec) Encode(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Decode implements Codec.
+func (JSONCodec) Decode(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// GobCodec encodes payloads using encoding/gob.
+type GobCodec struct{}
+
+// Encode implements Codec.
+func (GobCodec) Encode(v interface{}) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode implements Codec.
+func (GobCodec) Decode(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,346 @@
+// Package queue provides a durable work-queue on top of a rueidis.Client, using a redis
+// stream and consumer group for at-least-once delivery (XADD to enqueue, XREADGROUP to
+// dequeue, XACK to confirm). Because redis streams already load-balance pending entries
+// across every consumer reading the same group, a Queue needs no client-side rebalancing
+// logic: adding or removing consumers for a Group/Stream just changes who XREADGROUP
+// hands entries to next.
+package queue
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+// ErrNoMessage is returned by Pop when no message became available before the given
+// timeout elapsed. BatchPop instead returns an empty slice and a nil error in that case.
+var ErrNoMessage = errors.New("rueidis/queue: no message available")
+
+// Metrics receives counters from a Queue. Any field may be left nil; a Queue calls only
+// the hooks it has. Intended to be backed by Prometheus counters/histograms.
+type Metrics struct {
+	Enqueued     func(stream string)
+	Dequeued     func(stream string)
+	Acked        func(stream string)
+	Nacked       func(stream string)
+	DeadLettered func(stream string)
+}
+
+// Options configures a Queue.
+type Options struct {
+	Stream   string // redis stream key backing the queue
+	Group    string // consumer group name
+	Consumer string // this consumer's name within Group, must be unique per process
+
+	Codec Codec // defaults to JSONCodec
+
+	// VisibilityTimeout is how long a delivered-but-unacked message is hidden from other
+	// consumers before it becomes eligible for reclaiming via Reclaim. Defaults to 30s.
+	VisibilityTimeout time.Duration
+
+	// MaxRetries is the number of deliveries after which a message is moved to
+	// DeadLetterStream (if set) and acked, instead of being redelivered again. Zero means
+	// unlimited retries.
+	MaxRetries int64
+	// DeadLetterStream, if set, receives the payload of messages that reach MaxRetries.
+	DeadLetterStream string
+
+	// ReclaimBatchSize is how many idle entries Run claims per VisibilityTimeout tick.
+	// Defaults to 16.
+	ReclaimBatchSize int64
+
+	Metrics Metrics
+}
+
+// Message is a single unit of work dequeued from a Queue.
+type Message struct {
+	ID         string
+	Payload    []byte
+	Deliveries int64
+}
+
+// Queue is a durable work-queue backed by a redis stream and consumer group.
+type Queue struct {
+	client rueidis.Client
+	opts   Options
+}
+
+// New creates a Queue, creating its consumer group (and the underlying stream, via
+// MKSTREAM) if they do not already exist.
+func New(ctx context.Context, client rueidis.Client, opts Options) (*Queue, error) {
+	if opts.Stream == "" || opts.Group == "" || opts.Consumer == "" {
+		return nil, errors.New("rueidis/queue: Stream, Group and Consumer are required")
+	}
+	if opts.Codec == nil {
+		opts.Codec = JSONCodec{}
+	}
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = 30 * time.Second
+	}
+	if opts.ReclaimBatchSize <= 0 {
+		opts.ReclaimBatchSize = 16
+	}
+	q := &Queue{client: client, opts: opts}
+	err := client.Do(ctx, client.B().XgroupCreate().Key(opts.Stream).Group(opts.Group).Id("$").Mkstream().Build()).Error()
+	if err != nil && !isBusyGroupErr(err) {
+		return nil, err
+	}
+	return q, nil
+}
+
+func isBusyGroupErr(err error) bool {
+	var rerr *rueidis.RedisError
+	return errors.As(err, &rerr) && strings.HasPrefix(rerr.Error(), "BUSYGROUP")
+}
+
+// Push enqueues a raw payload and returns the stream entry ID redis assigned to it.
+func (q *Queue) Push(ctx context.Context, payload []byte) (string, error) {
+	id, err := q.client.Do(ctx, q.client.B().Xadd().Key(q.opts.Stream).Id("*").FieldValue().FieldValue("payload", string(payload)).Build()).ToString()
+	if err != nil {
+		return "", err
+	}
+	if q.opts.Metrics.Enqueued != nil {
+		q.opts.Metrics.Enqueued(q.opts.Stream)
+	}
+	return id, nil
+}
+
+// Enqueue encodes v with the Queue's Codec and pushes it.
+func Enqueue[T any](ctx context.Context, q *Queue, v T) (string, error) {
+	data, err := q.opts.Codec.Encode(v)
+	if err != nil {
+		return "", err
+	}
+	return q.Push(ctx, data)
+}
+
+// Pop dequeues a single message, blocking up to timeout for one to become available.
+// A zero timeout blocks indefinitely.
+func (q *Queue) Pop(ctx context.Context, timeout time.Duration) (Message, error) {
+	msgs, err := q.BatchPop(ctx, 1, timeout)
+	if err != nil {
+		return Message{}, err
+	}
+	if len(msgs) == 0 {
+		return Message{}, ErrNoMessage
+	}
+	return msgs[0], nil
+}
+
+// BatchPop dequeues up to n messages, blocking up to timeout for at least one to become
+// available. A zero timeout blocks indefinitely. It may return fewer than n messages.
+func (q *Queue) BatchPop(ctx context.Context, n int64, timeout time.Duration) ([]Message, error) {
+	result := q.client.Do(ctx, q.client.B().Xreadgroup().Group(q.opts.Group, q.opts.Consumer).Block(timeout.Milliseconds()).Count(n).Streams().Key(q.opts.Stream).Id(">").Build())
+	reply, err := xReadEntries(result, q.opts.Stream)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]Message, 0, len(reply))
+	for _, e := range reply {
+		// Id(">") above only reads entries never delivered to any consumer before, so
+		// their delivery count is always 1; no need to round-trip through XPENDING here.
+		msgs = append(msgs, Message{ID: e.ID, Payload: []byte(e.FieldValues["payload"]), Deliveries: 1})
+	}
+	if q.opts.Metrics.Dequeued != nil {
+		for range msgs {
+			q.opts.Metrics.Dequeued(q.opts.Stream)
+		}
+	}
+	return msgs, nil
+}
+
+// Dequeue pops a single message and decodes its payload with the Queue's Codec.
+func Dequeue[T any](ctx context.Context, q *Queue, timeout time.Duration) (T, Message, error) {
+	var v T
+	msg, err := q.Pop(ctx, timeout)
+	if err != nil {
+		return v, msg, err
+	}
+	err = q.opts.Codec.Decode(msg.Payload, &v)
+	return v, msg, err
+}
+
+// xReadEntries unwraps a XREADGROUP/XREAD reply down to the XRANGE-shaped entries for a
+// single stream. Unlike XRANGE/XAUTOCLAIM, a multi-stream XREADGROUP reply wraps those
+// entries keyed by stream name: a RESP3 map (rueidis' default) or, under RESP2, an array
+// of [stream name, entries] pairs.
+func xReadEntries(result rueidis.RedisResult, stream string) ([]rueidis.XRangeEntry, error) {
+	msg, err := result.ToMessage()
+	if rueidis.IsRedisNil(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if msg.Kind() == '%' {
+		streams, err := msg.ToMap()
+		if err != nil {
+			return nil, err
+		}
+		entries, ok := streams[stream]
+		if !ok {
+			return nil, nil
+		}
+		return entries.AsXRangeEntries()
+	}
+	pairs, err := msg.ToArray()
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range pairs {
+		kv, err := pair.ToArray()
+		if err != nil || len(kv) != 2 {
+			continue
+		}
+		if name, err := kv[0].ToString(); err == nil && name == stream {
+			return kv[1].AsXRangeEntries()
+		}
+	}
+	return nil, nil
+}
+
+func (q *Queue) deliveryCount(ctx context.Context, id string) (int64, error) {
+	arr, err := q.client.Do(ctx, q.client.B().Xpending().Key(q.opts.Stream).Group(q.opts.Group).Idle(0).Start(id).End(id).Count(1).Build()).ToArray()
+	if err != nil || len(arr) == 0 {
+		return 1, err
+	}
+	entry, err := arr[0].ToArray()
+	if err != nil || len(entry) < 4 {
+		return 1, err
+	}
+	return entry[3].ToInt64()
+}
+
+// Ack confirms successful processing of a message, removing it from the group's
+// pending-entries list.
+func (q *Queue) Ack(ctx context.Context, msg Message) error {
+	err := q.client.Do(ctx, q.client.B().Xack().Key(q.opts.Stream).Group(q.opts.Group).Id(msg.ID).Build()).Error()
+	if err == nil && q.opts.Metrics.Acked != nil {
+		q.opts.Metrics.Acked(q.opts.Stream)
+	}
+	return err
+}
+
+// Nack records a failed processing attempt. msg stays in the group's pending-entries
+// list (PEL): BatchPop never redelivers it, since it only reads new entries via
+// Id(">"); it becomes eligible for redelivery only once Reclaim claims it after
+// VisibilityTimeout has elapsed. If MaxRetries is set and msg has reached it, Nack
+// instead moves the message to DeadLetterStream (if set) and acks it, so it is not
+// reclaimed again. Below MaxRetries, Nack is a no-op beyond the metric hook: it
+// deliberately leaves the entry in the PEL for a future Reclaim to pick up.
+func (q *Queue) Nack(ctx context.Context, msg Message) error {
+	if q.opts.Metrics.Nacked != nil {
+		q.opts.Metrics.Nacked(q.opts.Stream)
+	}
+	if q.opts.MaxRetries <= 0 || msg.Deliveries < q.opts.MaxRetries {
+		return nil
+	}
+	if q.opts.DeadLetterStream != "" {
+		if err := q.client.Do(ctx, q.client.B().Xadd().Key(q.opts.DeadLetterStream).Id("*").FieldValue().FieldValue("payload", string(msg.Payload)).FieldValue("source_id", msg.ID).Build()).Error(); err != nil {
+			return err
+		}
+		if q.opts.Metrics.DeadLettered != nil {
+			q.opts.Metrics.DeadLettered(q.opts.Stream)
+		}
+	}
+	return q.Ack(ctx, msg)
+}
+
+// Reclaim claims messages that have been idle for at least VisibilityTimeout (delivered
+// to some consumer but never acked or nacked, ex. that consumer crashed) and hands them
+// to this Queue's Consumer. It should be called periodically by at least one consumer.
+func (q *Queue) Reclaim(ctx context.Context, n int64) ([]Message, error) {
+	entries, err := q.client.Do(ctx, q.client.B().Xautoclaim().Key(q.opts.Stream).Group(q.opts.Group).Consumer(q.opts.Consumer).MinIdleTime(strconv.FormatInt(q.opts.VisibilityTimeout.Milliseconds(), 10)).Start("0-0").Count(n).Build()).ToArray()
+	if err != nil || len(entries) < 2 {
+		return nil, err
+	}
+	claimed, err := entries[1].AsXRangeEntries()
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]Message, 0, len(claimed))
+	for _, e := range claimed {
+		deliveries, _ := q.deliveryCount(ctx, e.ID)
+		msgs = append(msgs, Message{ID: e.ID, Payload: []byte(e.FieldValues["payload"]), Deliveries: deliveries})
+	}
+	return msgs, nil
+}
+
+// Handler processes a single Message. Returning a nil error acks the message; a non-nil
+// error nacks it.
+type Handler func(ctx context.Context, msg Message) error
+
+// Run starts workers goroutines that BatchPop and invoke handle in a loop, plus one
+// goroutine that calls Reclaim every VisibilityTimeout to recover messages left pending
+// by a crashed handler or consumer (and to dead-letter them past MaxRetries, via Nack).
+// This is what makes the PEL recovery and dead-lettering described on Nack and Reclaim
+// actually happen for callers of Run; a caller driving BatchPop/Ack/Nack directly instead
+// of Run must run its own Reclaim loop to get the same guarantees. Run blocks until ctx
+// is canceled and every goroutine has returned.
+func (q *Queue) Run(ctx context.Context, workers int, handle Handler) {
+	var wg sync.WaitGroup
+	wg.Add(workers + 1)
+	go func() {
+		defer wg.Done()
+		q.reclaimLoop(ctx, handle)
+	}()
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				msgs, err := q.BatchPop(ctx, 1, 5*time.Second)
+				if err != nil {
+					// Back off so a persistently failing BatchPop (ex. redis
+					// unreachable, so Block never actually blocks) doesn't spin
+					// the worker hot.
+					select {
+					case <-ctx.Done():
+					case <-time.After(time.Second):
+					}
+					continue
+				}
+				if len(msgs) == 0 {
+					continue
+				}
+				for _, msg := range msgs {
+					if err := handle(ctx, msg); err != nil {
+						_ = q.Nack(ctx, msg)
+					} else {
+						_ = q.Ack(ctx, msg)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// reclaimLoop periodically claims idle pending entries and runs them through handle,
+// same as the BatchPop workers in Run.
+func (q *Queue) reclaimLoop(ctx context.Context, handle Handler) {
+	ticker := time.NewTicker(q.opts.VisibilityTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			msgs, err := q.Reclaim(ctx, q.opts.ReclaimBatchSize)
+			if err != nil {
+				continue
+			}
+			for _, msg := range msgs {
+				if err := handle(ctx, msg); err != nil {
+					_ = q.Nack(ctx, msg)
+				} else {
+					_ = q.Ack(ctx, msg)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package rueidis
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrScanDestination is returned by Scan when the destination passed in is not
+// a non-nil pointer to a struct.
+var ErrScanDestination = errors.New("rueidis: Scan destination must be a non-nil pointer to a struct")
+
+// ScanFieldError is returned by Scan when a struct field tagged as "required" is
+// absent from the redis response being scanned.
+type ScanFieldError struct {
+	Type  reflect.Type
+	Field string
+}
+
+func (e *ScanFieldError) Error() string {
+	return fmt.Sprintf("rueidis: field %q required by struct %s is missing from the response", e.Field, e.Type)
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+type scanField struct {
+	index    []int
+	required bool
+}
+
+type scanFields struct {
+	byName map[string]scanField
+}
+
+var scanFieldsCache sync.Map // map[reflect.Type]*scanFields
+
+// scanFieldsOf returns the cached field map of t, building and caching it on first use.
+func scanFieldsOf(t reflect.Type) *scanFields {
+	if v, ok := scanFieldsCache.Load(t); ok {
+		return v.(*scanFields)
+	}
+	sf := &scanFields{byName: make(map[string]scanField)}
+	buildScanFields(t, nil, sf)
+	v, _ := scanFieldsCache.LoadOrStore(t, sf)
+	return v.(*scanFields)
+}
+
+func buildScanFields(t reflect.Type, index []int, sf *scanFields) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue // unexported field
+		}
+		tag := f.Tag.Get("redis")
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+		idx := append(append([]int{}, index...), i)
+		if name == "" {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				buildScanFields(f.Type, idx, sf)
+				continue
+			}
+			name = f.Name
+		}
+		sf.byName[name] = scanField{index: idx, required: opts.Contains("required")}
+	}
+}
+
+type tagOptions string
+
+func parseTag(tag string) (name string, opts tagOptions) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tagOptions(tag[i+1:])
+	}
+	return tag, ""
+}
+
+func (o tagOptions) Contains(name string) bool {
+	s := string(o)
+	for s != "" {
+		var next string
+		if i := strings.Index(s, ","); i >= 0 {
+			s, next = s[:i], s[i+1:]
+		}
+		if s == name {
+			return true
+		}
+		s = next
+	}
+	return false
+}
+
+// Scan decodes a RESP3 map response, or a flat array response such as the result of
+// HGETALL, into the struct pointed to by v using `redis:"field_name"` struct tags.
+// Tagging a field with `redis:"field_name,required"` makes Scan return a *ScanFieldError
+// if that field is absent from the response. Nested structs, time.Time, pointers, slices,
+// encoding.TextUnmarshaler and json.Unmarshaler fields are all supported.
+func (m *RedisMessage) Scan(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrScanDestination
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return ErrScanDestination
+	}
+	data, err := m.toScanMap()
+	if err != nil {
+		return err
+	}
+	return scanInto(rv, data)
+}
+
+// Scan delegates to RedisMessage.Scan
+func (r RedisResult) Scan(v interface{}) error {
+	if err := r.Error(); err != nil {
+		return err
+	}
+	return r.val.Scan(v)
+}
+
+func (m *RedisMessage) toScanMap() (map[string]RedisMessage, error) {
+	if m.typ == '%' {
+		return m.ToMap()
+	}
+	if m.typ == '*' || m.typ == '~' {
+		if len(m.values)%2 != 0 {
+			return nil, fmt.Errorf("rueidis: Scan requires an even number of elements in an array response, got %d", len(m.values))
+		}
+		r := make(map[string]RedisMessage, len(m.values)/2)
+		for i := 0; i < len(m.values); i += 2 {
+			k, err := m.values[i].ToString()
+			if err != nil {
+				return nil, err
+			}
+			r[k] = m.values[i+1]
+		}
+		return r, nil
+	}
+	if err := m.Error(); err != nil {
+		return nil, err
+	}
+	panic(fmt.Sprintf("redis message type %c cannot be scanned into a struct", m.typ))
+}
+
+func scanInto(rv reflect.Value, data map[string]RedisMessage) error {
+	sf := scanFieldsOf(rv.Type())
+	for name, f := range sf.byName {
+		msg, ok := data[name]
+		if !ok {
+			if f.required {
+				return &ScanFieldError{Type: rv.Type(), Field: name}
+			}
+			continue
+		}
+		fv := rv
+		for _, i := range f.index {
+			fv = fv.Field(i)
+		}
+		if err := scanValue(fv, &msg); err != nil {
+			return fmt.Errorf("rueidis: field %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func scanValue(fv reflect.Value, m *RedisMessage) error {
+	if fv.Kind() == reflect.Ptr {
+		if m.IsNil() {
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return scanValue(fv.Elem(), m)
+	}
+	if fv.CanAddr() {
+		addr := fv.Addr()
+		if addr.Type().Implements(textUnmarshalerType) {
+			s, err := m.ToString()
+			if err != nil {
+				return err
+			}
+			return addr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		}
+		if addr.Type().Implements(jsonUnmarshalerType) {
+			s, err := m.ToString()
+			if err != nil {
+				return err
+			}
+			return addr.Interface().(json.Unmarshaler).UnmarshalJSON([]byte(s))
+		}
+	}
+	if fv.Type() == timeType {
+		s, err := m.ToString()
+		if err != nil {
+			return err
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		s, err := m.ToString()
+		if err != nil {
+			return err
+		}
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := scanBool(m)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := scanInt64(m)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := scanInt64(m)
+		if err != nil {
+			return err
+		}
+		fv.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		f, err := scanFloat64(m)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Struct:
+		data, err := m.toScanMap()
+		if err != nil {
+			return err
+		}
+		return scanInto(fv, data)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() == reflect.Uint8 {
+			s, err := m.ToString()
+			if err != nil {
+				return err
+			}
+			fv.SetBytes([]byte(s))
+			return nil
+		}
+		values, err := m.ToArray()
+		if err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i := range values {
+			if err := scanValue(s.Index(i), &values[i]); err != nil {
+				return err
+			}
+		}
+		fv.Set(s)
+	default:
+		return fmt.Errorf("rueidis: unsupported scan destination kind %s", fv.Kind())
+	}
+	return nil
+}
+
+func scanBool(m *RedisMessage) (bool, error) {
+	if m.typ == '#' {
+		return m.ToBool()
+	}
+	s, err := m.ToString()
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(s)
+}
+
+func scanInt64(m *RedisMessage) (int64, error) {
+	if m.typ == ':' {
+		return m.ToInt64()
+	}
+	s, err := m.ToString()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func scanFloat64(m *RedisMessage) (float64, error) {
+	if m.typ == ',' {
+		return m.ToFloat64()
+	}
+	s, err := m.ToString()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(s, 64)
+}